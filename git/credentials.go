@@ -0,0 +1,295 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// Credentials holds resolved auth for a single host, ready to be injected
+// into a git child process by applyCredentials.
+type Credentials struct {
+	Host     string
+	Username string
+	Password string
+	// Header, when set, is injected as a `http.extraHeader` instead of
+	// rewriting the remote URL (used for cookie-based auth).
+	Header string
+}
+
+// ResolveCredentials looks up credentials for remoteURI's host by trying,
+// in order: $HOME/.netrc, git's configured http.cookiefile, then falling
+// back to GIT_ASKPASS/env (handled natively by the git binary, so no
+// Credentials are returned for that case). repoDir, when non-empty, is
+// consulted for a repo-local http.cookiefile override; pass "" when there
+// is no existing checkout yet (e.g. before a fresh Clone).
+// Returns nil, nil if nothing applies.
+func ResolveCredentials(repoDir, remoteURI string) (*Credentials, error) {
+	host := hostOf(remoteURI)
+	if host == "" {
+		return nil, nil
+	}
+
+	creds, err := netrcCredentials(host)
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		return creds, nil
+	}
+
+	creds, err = cookieFileCredentials(repoDir, host)
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		return creds, nil
+	}
+
+	// Nothing found: let GIT_ASKPASS/env, if set, be handled by git itself.
+	return nil, nil
+}
+
+// hostOf extracts the host from a `scheme://host/path` or `git@host:path`
+// scp-like remote URI. Returns "" for anything else, including plain
+// `host/path` forms and local paths.
+func hostOf(remoteURI string) string {
+	if strings.Contains(remoteURI, "://") {
+		u, err := url.Parse(remoteURI)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+
+	if atIndex := strings.Index(remoteURI, "@"); atIndex != -1 {
+		rest := remoteURI[atIndex+1:]
+		if colonIndex := strings.Index(rest, ":"); colonIndex != -1 {
+			return rest[:colonIndex]
+		}
+	}
+
+	return ""
+}
+
+// netrcCredentials parses $HOME/.netrc for a `machine <host>` entry.
+func netrcCredentials(host string) (*Credentials, error) {
+	file, err := os.Open(os.Getenv("HOME") + "/.netrc")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var tokens []string
+	inMacroBody := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacroBody {
+			// A macdef's body runs until the next blank line; skip it so its
+			// contents aren't mistaken for machine/login/password tokens.
+			if strings.TrimSpace(line) == "" {
+				inMacroBody = false
+			}
+			continue
+		}
+		for _, token := range strings.Fields(line) {
+			if token == "macdef" {
+				inMacroBody = true
+				break
+			}
+			tokens = append(tokens, token)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return findNetrcEntry(tokens, host), nil
+}
+
+// findNetrcEntry walks tokens one at a time looking for a `machine <host>`
+// entry, collecting its `login`/`password` values until the next `machine`
+// or `default` keyword (whichever comes first) ends it. Walking token-by-
+// token, rather than assuming strict key/value pairing, means a stray or
+// unrecognized token can't desynchronize parsing and bleed into the next
+// entry's credentials.
+func findNetrcEntry(tokens []string, host string) *Credentials {
+	var creds *Credentials
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			if creds != nil {
+				return creds
+			}
+			if i+1 < len(tokens) && tokens[i+1] == host {
+				creds = &Credentials{Host: host}
+				i++
+			}
+		case "default":
+			if creds != nil {
+				return creds
+			}
+		case "login":
+			if creds != nil && i+1 < len(tokens) {
+				creds.Username = tokens[i+1]
+				i++
+			}
+		case "password":
+			if creds != nil && i+1 < len(tokens) {
+				creds.Password = tokens[i+1]
+				i++
+			}
+		}
+	}
+
+	return creds
+}
+
+// gitConfigSearchPaths returns the git config files that may carry
+// `http.cookiefile`, most specific first: a repo-local config (if repoDir
+// is known), then the user's global config (honoring a GIT_CONFIG_GLOBAL
+// override), then the XDG config as a last resort. This mirrors git's own
+// config precedence closely enough for credential lookup, without needing
+// to shell out to `git config` to get the fully merged view.
+func gitConfigSearchPaths(repoDir string) []string {
+	var paths []string
+	if repoDir != "" {
+		paths = append(paths, path.Join(repoDir, ".git", "config"))
+	}
+	if globalConfig := os.Getenv("GIT_CONFIG_GLOBAL"); globalConfig != "" {
+		paths = append(paths, globalConfig)
+	} else {
+		paths = append(paths, path.Join(os.Getenv("HOME"), ".gitconfig"))
+	}
+	paths = append(paths, path.Join(os.Getenv("HOME"), ".config", "git", "config"))
+	return paths
+}
+
+// cookieFileCredentials reads the `http.cookiefile` value from git config
+// and parses the Netscape cookie file it points to, matching host-scoped or
+// `.<domain>` wildcard entries. It parses git's config files directly
+// rather than shelling out to `git config`, so it works the same whether or
+// not a git binary is available (e.g. under the gitgo build tag).
+func cookieFileCredentials(repoDir, host string) (*Credentials, error) {
+	var cookieFilePath string
+	for _, configPath := range gitConfigSearchPaths(repoDir) {
+		if value, ok := gitConfigValue(configPath, "http", "cookiefile"); ok {
+			cookieFilePath = value
+			break
+		}
+	}
+	if cookieFilePath == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(cookieFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		columns := strings.Split(line, "\t")
+		if len(columns) < 7 {
+			continue
+		}
+
+		if !hostMatchesCookieDomain(host, columns[0]) {
+			continue
+		}
+
+		return &Credentials{
+			Host:   host,
+			Header: fmt.Sprintf("Cookie: %s=%s", columns[5], columns[6]),
+		}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func hostMatchesCookieDomain(host, cookieDomain string) bool {
+	if strings.HasPrefix(cookieDomain, ".") {
+		return host == cookieDomain[1:] || strings.HasSuffix(host, cookieDomain)
+	}
+	return host == cookieDomain
+}
+
+// gitConfigValue looks up key within section (e.g. section "http", key
+// "cookiefile") from the git config file at configPath, parsing git's
+// simple INI format directly.
+func gitConfigValue(configPath, section, key string) (value string, ok bool) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	currentSection := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			currentSection = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		if currentSection != section {
+			continue
+		}
+		keyValue := strings.SplitN(line, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		if strings.TrimSpace(keyValue[0]) == key {
+			value = strings.TrimSpace(keyValue[1])
+			ok = true
+		}
+	}
+
+	return value, ok
+}
+
+// applyCredentials returns the remote URI to hand to the git child process
+// and any extra `git -c ...` arguments needed to authenticate, injecting
+// creds either by rewriting the URL with embedded basic-auth or via a
+// `http.extraHeader` override.
+func applyCredentials(remoteURI string, creds *Credentials) (effectiveURI string, extraArgs []string) {
+	if creds == nil {
+		return remoteURI, nil
+	}
+
+	if creds.Header != "" {
+		return remoteURI, []string{"-c", "http.extraHeader=" + creds.Header}
+	}
+
+	if creds.Username != "" {
+		if u, err := url.Parse(remoteURI); err == nil && u.Scheme != "" {
+			u.User = url.UserPassword(creds.Username, creds.Password)
+			return u.String(), nil
+		}
+	}
+
+	return remoteURI, nil
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestGitConfigValue(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "godm-gitconfig-test")
+	if err != nil {
+		t.Fatalf("failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configPath := path.Join(tmpDir, "gitconfig")
+	content := "[user]\n\tname = Alice\n[http]\n\tcookiefile = /home/alice/.gitcookies\n"
+	if err := ioutil.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed writing config file: %s", err)
+	}
+
+	value, ok := gitConfigValue(configPath, "http", "cookiefile")
+	if !ok || value != "/home/alice/.gitcookies" {
+		t.Errorf("gitConfigValue(http.cookiefile) = (%q, %v), want (/home/alice/.gitcookies, true)", value, ok)
+	}
+
+	if _, ok := gitConfigValue(configPath, "http", "proxy"); ok {
+		t.Errorf("gitConfigValue(http.proxy) = ok, want not found")
+	}
+
+	if _, ok := gitConfigValue(path.Join(tmpDir, "missing"), "http", "cookiefile"); ok {
+		t.Errorf("gitConfigValue on a missing file = ok, want not found")
+	}
+}
+
+func TestFindNetrcEntry(t *testing.T) {
+	testCases := map[string]struct {
+		tokens           []string
+		host             string
+		expectedUsername string
+		expectedPassword string
+		expectNil        bool
+	}{
+		"simple match": {
+			tokens:           []string{"machine", "example.com", "login", "alice", "password", "secret"},
+			host:             "example.com",
+			expectedUsername: "alice",
+			expectedPassword: "secret",
+		},
+		"no match": {
+			tokens:    []string{"machine", "other.com", "login", "alice", "password", "secret"},
+			host:      "example.com",
+			expectNil: true,
+		},
+		"stops at next machine": {
+			tokens: []string{
+				"machine", "example.com", "login", "alice", "password", "secret",
+				"machine", "other.com", "login", "bob", "password", "hunter2",
+			},
+			host:             "example.com",
+			expectedUsername: "alice",
+			expectedPassword: "secret",
+		},
+		"stops at default": {
+			tokens:           []string{"machine", "example.com", "login", "alice", "password", "secret", "default", "login", "anon"},
+			host:             "example.com",
+			expectedUsername: "alice",
+			expectedPassword: "secret",
+		},
+		"stray unpaired token does not desync the next entry": {
+			tokens: []string{
+				"machine", "example.com", "account", "login", "alice", "password", "secret",
+				"machine", "other.com", "login", "bob", "password", "hunter2",
+			},
+			host:             "other.com",
+			expectedUsername: "bob",
+			expectedPassword: "hunter2",
+		},
+	}
+
+	for caseName, testCase := range testCases {
+		creds := findNetrcEntry(testCase.tokens, testCase.host)
+
+		if testCase.expectNil {
+			if creds != nil {
+				t.Errorf("%q: expected nil credentials, got %+v", caseName, creds)
+			}
+			continue
+		}
+
+		if creds == nil {
+			t.Errorf("%q: expected credentials, got nil", caseName)
+			continue
+		}
+		if creds.Username != testCase.expectedUsername {
+			t.Errorf("%q: expected username %q, got %q", caseName, testCase.expectedUsername, creds.Username)
+		}
+		if creds.Password != testCase.expectedPassword {
+			t.Errorf("%q: expected password %q, got %q", caseName, testCase.expectedPassword, creds.Password)
+		}
+	}
+}
+
+func TestHostMatchesCookieDomain(t *testing.T) {
+	testCases := map[string]struct {
+		host         string
+		cookieDomain string
+		expected     bool
+	}{
+		"exact match":        {host: "example.com", cookieDomain: "example.com", expected: true},
+		"exact mismatch":     {host: "example.com", cookieDomain: "other.com", expected: false},
+		"wildcard match":     {host: "sub.example.com", cookieDomain: ".example.com", expected: true},
+		"wildcard apex":      {host: "example.com", cookieDomain: ".example.com", expected: true},
+		"wildcard mismatch":  {host: "example.com.evil.com", cookieDomain: ".example.com", expected: false},
+		"unrelated wildcard": {host: "example.com", cookieDomain: ".other.com", expected: false},
+	}
+
+	for caseName, testCase := range testCases {
+		actual := hostMatchesCookieDomain(testCase.host, testCase.cookieDomain)
+		if actual != testCase.expected {
+			t.Errorf("%q: hostMatchesCookieDomain(%q, %q) = %v, want %v", caseName, testCase.host, testCase.cookieDomain, actual, testCase.expected)
+		}
+	}
+}
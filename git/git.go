@@ -1,12 +1,14 @@
+// +build !gitgo
+
 package git
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	exec2 "os/exec"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hectorj/godm/exec"
@@ -22,12 +24,48 @@ func init() {
 	}
 }
 
-func (self gitService) Clone(targetPath, remoteURI string) error {
-	return exec.Cmd("", gitCommand, "clone", remoteURI, targetPath).GetError()
+// newDefaultService returns the exec-based backend, which shells out to the
+// `git` binary. Selected unless the gitgo build tag is used.
+func newDefaultService() VCSService {
+	return gitService{}
+}
+
+type gitService struct{}
+
+func (self gitService) Clone(targetPath, remoteURI string, options CloneOptions) error {
+	// No repoDir yet: the destination doesn't exist until after the clone.
+	creds, err := ResolveCredentials("", remoteURI)
+	if err != nil {
+		return err
+	}
+	effectiveURI, args := applyCredentials(remoteURI, creds)
+
+	args = append(args, "clone")
+	if options.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(options.Depth))
+	}
+	if options.Branch != "" {
+		args = append(args, "-b", options.Branch)
+	}
+	if options.Recursive {
+		args = append(args, "--recursive")
+	}
+	if options.ShallowSubmodules {
+		args = append(args, "--shallow-submodules")
+	}
+	args = append(args, effectiveURI, targetPath)
+	return exec.Cmd("", gitCommand, args...).GetError()
 }
 
 func (self gitService) AddSubmodule(repoDir, remoteURI, targetPath string) error {
-	return exec.Cmd(repoDir, gitCommand, "submodule", "add", "-f", remoteURI, targetPath).GetError()
+	creds, err := ResolveCredentials(repoDir, remoteURI)
+	if err != nil {
+		return err
+	}
+	effectiveURI, args := applyCredentials(remoteURI, creds)
+
+	args = append(args, "submodule", "add", "-f", effectiveURI, targetPath)
+	return exec.Cmd(repoDir, gitCommand, args...).GetError()
 }
 
 func (self gitService) RemoveSubmodule(repoDir, targetPath string) error {
@@ -50,8 +88,6 @@ func (self gitService) CheckoutCommit(repoDir, commitHash string) error {
 
 var remoteExtractRegexp = regexp.MustCompile(`^([^\s]+)\s+([^\s]+) \(fetch\)`)
 
-var ErrNoRemote = errors.New("No remote found")
-
 func (self gitService) GetRemoteURI(repoDir string) (string, error) {
 	result := exec.Cmd(repoDir, gitCommand, "remote", "-v")
 
@@ -78,8 +114,6 @@ func (self gitService) GetCurrentCommitHash(repoDir string) (string, error) {
 	return strings.Trim(string(result.GetStdout()), "\n"), nil
 }
 
-var ErrNotAGitRepository = errors.New("Not a git repository")
-
 func (self gitService) GetRootDir(dir string) (string, error) {
 	result := exec.Cmd(dir, gitCommand, "rev-parse", "--show-toplevel")
 
@@ -96,10 +130,17 @@ func (self gitService) InitRepo(repoDir string) error {
 	return exec.Cmd(repoDir, gitCommand, "init").GetError()
 }
 
-func (self gitService) InitSubmodules(repoDir string) error {
+func (self gitService) InitSubmodules(repoDir string, recursive bool) error {
+	// `git submodule init` has no `--recursive` flag (only `update` does);
+	// init only ever registers the repo's own direct submodules, and nested
+	// ones are brought in by UpdateSubmodules(recursive=true) instead.
 	return exec.Cmd(repoDir, gitCommand, "submodule", "init").GetError()
 }
 
-func (self gitService) UpdateSubmodules(repoDir string) error {
-	return exec.Cmd(repoDir, gitCommand, "submodule", "update").GetError()
+func (self gitService) UpdateSubmodules(repoDir string, recursive bool) error {
+	args := []string{"submodule", "update"}
+	if recursive {
+		args = append(args, "--recursive")
+	}
+	return exec.Cmd(repoDir, gitCommand, args...).GetError()
 }
@@ -0,0 +1,171 @@
+// +build gitgo
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// newDefaultService returns the pure-Go backend, built on go-git. Unlike the
+// exec-based backend it never shells out to a `git` binary, so it works in
+// environments where one isn't installed (containers, serverless) and it
+// can be driven purely in-memory for tests, without git.NewGitStub.
+// Selected when the gitgo build tag is used.
+func newDefaultService() VCSService {
+	return goGitService{}
+}
+
+type goGitService struct{}
+
+func (self goGitService) Clone(targetPath, remoteURI string, options CloneOptions) error {
+	// No repoDir yet: the destination doesn't exist until after the clone.
+	creds, err := ResolveCredentials("", remoteURI)
+	if err != nil {
+		return err
+	}
+	effectiveURI, _ := applyCredentials(remoteURI, creds)
+
+	cloneOptions := &gogit.CloneOptions{
+		URL:               effectiveURI,
+		Depth:             options.Depth,
+		RecurseSubmodules: gogit.NoRecurseSubmodules,
+	}
+	// Branch is looked up as a branch name only (tags are not resolved), and
+	// ShallowSubmodules has no effect in this backend.
+	if options.Branch != "" {
+		cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(options.Branch)
+	}
+	if options.Recursive {
+		cloneOptions.RecurseSubmodules = gogit.DefaultSubmoduleRecursionDepth
+	}
+
+	_, err = gogit.PlainClone(targetPath, false, cloneOptions)
+	return err
+}
+
+func (self goGitService) AddSubmodule(repoDir, remoteURI, targetPath string) error {
+	repo, err := gogit.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	relativePath, err := filepath.Rel(repoDir, targetPath)
+	if err != nil {
+		return err
+	}
+
+	return worktree.AddSubmodule(&gogit.SubmoduleConfig{
+		Name: relativePath,
+		Path: relativePath,
+		URL:  remoteURI,
+	})
+}
+
+func (self goGitService) RemoveSubmodule(repoDir, targetPath string) error {
+	return os.RemoveAll(filepath.Join(repoDir, targetPath))
+}
+
+func (self goGitService) CheckoutCommit(repoDir, commitHash string) error {
+	repo, err := gogit.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Checkout(&gogit.CheckoutOptions{
+		Hash: plumbing.NewHash(commitHash),
+	})
+}
+
+func (self goGitService) GetRemoteURI(repoDir string) (string, error) {
+	repo, err := gogit.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return "", err
+	}
+	if len(remotes) == 0 || len(remotes[0].Config().URLs) == 0 {
+		return "", ErrNoRemote
+	}
+	return remotes[0].Config().URLs[0], nil
+}
+
+func (self goGitService) GetCurrentCommitHash(repoDir string) (string, error) {
+	repo, err := gogit.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+func (self goGitService) GetRootDir(dir string) (string, error) {
+	repo, err := gogit.PlainOpenWithOptions(dir, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		if err == gogit.ErrRepositoryNotExists {
+			return "", ErrNotAGitRepository
+		}
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+func (self goGitService) InitRepo(repoDir string) error {
+	_, err := gogit.PlainInit(repoDir, false)
+	return err
+}
+
+func (self goGitService) InitSubmodules(repoDir string, recursive bool) error {
+	// go-git initializes submodule config as part of AddSubmodule/Update;
+	// nothing separate to do here.
+	return nil
+}
+
+func (self goGitService) UpdateSubmodules(repoDir string, recursive bool) error {
+	repo, err := gogit.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return err
+	}
+
+	recursionDepth := gogit.NoRecurseSubmodules
+	if recursive {
+		recursionDepth = gogit.DefaultSubmoduleRecursionDepth
+	}
+
+	for _, submodule := range submodules {
+		if err := submodule.Update(&gogit.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: recursionDepth,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package git
+
+import "errors"
+
+// CloneOptions controls how a clone is performed.
+// A zero-value CloneOptions does a plain, full, non-recursive clone.
+type CloneOptions struct {
+	// Depth, when > 0, passes `--depth N` to do a shallow clone.
+	Depth int
+	// Branch, when set, passes `-b BRANCH` to clone a specific branch or tag.
+	Branch string
+	// Recursive passes `--recursive` to clone submodules along with the repo.
+	Recursive bool
+	// ShallowSubmodules passes `--shallow-submodules`, limiting submodule
+	// history to the latest commit. Only meaningful together with Recursive.
+	ShallowSubmodules bool
+}
+
+var ErrNoRemote = errors.New("No remote found")
+
+var ErrNotAGitRepository = errors.New("Not a git repository")
+
+// VCSService is the set of version-control operations godm needs from its
+// backend. Two backends implement it: the exec-based `git` binary backend
+// (this package's default, built with the gitbin tag, the zero value of
+// which is also used when no build tag is given) and a pure-Go backend
+// backed by go-git (built with the gitgo tag).
+type VCSService interface {
+	Clone(targetPath, remoteURI string, options CloneOptions) error
+	AddSubmodule(repoDir, remoteURI, targetPath string) error
+	RemoveSubmodule(repoDir, targetPath string) error
+	CheckoutCommit(repoDir, commitHash string) error
+	GetRemoteURI(repoDir string) (string, error)
+	GetCurrentCommitHash(repoDir string) (string, error)
+	GetRootDir(dir string) (string, error)
+	InitRepo(repoDir string) error
+	InitSubmodules(repoDir string, recursive bool) error
+	UpdateSubmodules(repoDir string, recursive bool) error
+}
+
+// Service is the package-level VCS backend used throughout godm. It is
+// selected at compile time by the gitbin/gitgo build tags, and can be
+// overridden at runtime with SetService (exposed to callers of the godm
+// package as godm.SetVCSBackend).
+var Service VCSService = newDefaultService()
+
+// SetService overrides the package-level VCS backend.
+func SetService(service VCSService) {
+	Service = service
+}
@@ -0,0 +1,91 @@
+package godm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ImportPathResolver deduces the VCS root and clone URL for a bare import
+// path such as "github.com/foo/bar" or "golang.org/x/net", mirroring
+// `go get`'s own deduction rules: hardcoded handling for well-known hosts,
+// falling back to fetching the `?go-get=1` HTML meta tag for custom domains.
+type ImportPathResolver struct {
+	// HTTPGet fetches the `?go-get=1` page for custom domains.
+	// Defaults to http.Get; overridable for tests.
+	HTTPGet func(url string) (*http.Response, error)
+}
+
+// NewImportPathResolver returns a resolver using the default HTTP client.
+func NewImportPathResolver() *ImportPathResolver {
+	return &ImportPathResolver{HTTPGet: http.Get}
+}
+
+// ResolvedImportPath is the outcome of resolving a bare import path.
+type ResolvedImportPath struct {
+	// RepoRoot is the portion of importPath that identifies the repository,
+	// e.g. "github.com/foo/bar" for "github.com/foo/bar/pkg/sub".
+	RepoRoot string
+	// CloneURL is the fetchable git URI for the repository.
+	CloneURL string
+}
+
+// knownHosts maps a well-known host to the number of leading import-path
+// segments that make up its repo root, e.g. "github.com/foo/bar" is 3.
+var knownHosts = map[string]int{
+	"github.com":    3,
+	"bitbucket.org": 3,
+	"gitlab.com":    3,
+}
+
+// Resolve deduces the repo root and clone URL for importPath.
+func (self *ImportPathResolver) Resolve(importPath string) (*ResolvedImportPath, error) {
+	segments := strings.Split(importPath, "/")
+
+	if depth, known := knownHosts[segments[0]]; known {
+		if len(segments) < depth {
+			return nil, fmt.Errorf("import path %q is missing the repository name for host %q", importPath, segments[0])
+		}
+		repoRoot := strings.Join(segments[:depth], "/")
+		return &ResolvedImportPath{
+			RepoRoot: repoRoot,
+			CloneURL: "https://" + repoRoot,
+		}, nil
+	}
+
+	return self.resolveByMetaTag(importPath)
+}
+
+var goImportMetaRegexp = regexp.MustCompile(`<meta\s+name=["']go-import["']\s+content=["']([^"']+)["']\s*/?>`)
+
+// resolveByMetaTag handles custom domains by fetching `<importPath>?go-get=1`
+// and parsing its `<meta name="go-import" content="prefix vcs repo">` tag.
+func (self *ImportPathResolver) resolveByMetaTag(importPath string) (*ResolvedImportPath, error) {
+	resp, err := self.HTTPGet(fmt.Sprintf("https://%s?go-get=1", importPath))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := goImportMetaRegexp.FindStringSubmatch(string(body))
+	if matches == nil {
+		return nil, fmt.Errorf("no go-import meta tag found for %q", importPath)
+	}
+
+	fields := strings.Fields(matches[1])
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("malformed go-import meta tag for %q: %q", importPath, matches[1])
+	}
+
+	return &ResolvedImportPath{
+		RepoRoot: fields[0],
+		CloneURL: fields[2],
+	}, nil
+}
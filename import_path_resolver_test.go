@@ -0,0 +1,74 @@
+package godm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func fakeHTTPGet(body string) func(url string) (*http.Response, error) {
+	return func(url string) (*http.Response, error) {
+		return &http.Response{Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+func TestImportPathResolver_Resolve(t *testing.T) {
+	testCases := map[string]struct {
+		importPath       string
+		metaTagBody      string
+		expectedRepoRoot string
+		expectedCloneURL string
+		expectError      bool
+	}{
+		"github": {
+			importPath:       "github.com/foo/bar",
+			expectedRepoRoot: "github.com/foo/bar",
+			expectedCloneURL: "https://github.com/foo/bar",
+		},
+		"github with subpackage": {
+			importPath:       "github.com/foo/bar/pkg/sub",
+			expectedRepoRoot: "github.com/foo/bar",
+			expectedCloneURL: "https://github.com/foo/bar",
+		},
+		"bitbucket": {
+			importPath:       "bitbucket.org/foo/bar",
+			expectedRepoRoot: "bitbucket.org/foo/bar",
+			expectedCloneURL: "https://bitbucket.org/foo/bar",
+		},
+		"custom domain via go-import meta tag": {
+			importPath:       "golang.org/x/net",
+			metaTagBody:      `<html><head><meta name="go-import" content="golang.org/x/net git https://go.googlesource.com/net"></head></html>`,
+			expectedRepoRoot: "golang.org/x/net",
+			expectedCloneURL: "https://go.googlesource.com/net",
+		},
+		"custom domain with no meta tag": {
+			importPath:  "example.com/foo",
+			metaTagBody: `<html><head></head></html>`,
+			expectError: true,
+		},
+	}
+
+	for caseName, testCase := range testCases {
+		resolver := &ImportPathResolver{HTTPGet: fakeHTTPGet(testCase.metaTagBody)}
+
+		resolved, err := resolver.Resolve(testCase.importPath)
+
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("%q: expected an error, got none", caseName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", caseName, err)
+			continue
+		}
+		if resolved.RepoRoot != testCase.expectedRepoRoot {
+			t.Errorf("%q: expected repo root %q, got %q", caseName, testCase.expectedRepoRoot, resolved.RepoRoot)
+		}
+		if resolved.CloneURL != testCase.expectedCloneURL {
+			t.Errorf("%q: expected clone URL %q, got %q", caseName, testCase.expectedCloneURL, resolved.CloneURL)
+		}
+	}
+}
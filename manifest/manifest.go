@@ -0,0 +1,65 @@
+// Package manifest models a project's full vendor tree as a declarative
+// manifest, similar to the repo/jiri tools: a flat list of pinned projects
+// plus optional imports of other manifests.
+package manifest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Project is a single vendored dependency pinned to a specific revision,
+// mirroring a `<project name path remote revision remotebranch>` entry.
+type Project struct {
+	Name         string `xml:"name,attr" yaml:"name"`
+	Path         string `xml:"path,attr" yaml:"path"`
+	Remote       string `xml:"remote,attr" yaml:"remote"`
+	Revision     string `xml:"revision,attr" yaml:"revision"`
+	RemoteBranch string `xml:"remotebranch,attr" yaml:"remotebranch"`
+}
+
+// Import references another manifest to pull in, fetched from a remote git
+// repository before being parsed and merged in.
+type Import struct {
+	Name     string `xml:"name,attr" yaml:"name"`
+	Remote   string `xml:"remote,attr" yaml:"remote"`
+	Manifest string `xml:"manifest,attr" yaml:"manifest"`
+}
+
+// Manifest is the declarative description of a project's full vendor tree.
+type Manifest struct {
+	XMLName  xml.Name  `xml:"manifest" yaml:"-"`
+	Imports  []Import  `xml:"imports>import" yaml:"imports"`
+	Projects []Project `xml:"projects>project" yaml:"projects"`
+}
+
+// Parse decodes a manifest from either XML or YAML, sniffing the format
+// from the content since an XML manifest always starts with "<".
+func Parse(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "<") {
+		if err := xml.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("parsing XML manifest: %s", err)
+		}
+		return m, nil
+	}
+
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing YAML manifest: %s", err)
+	}
+	return m, nil
+}
+
+// ParseFile reads and parses the manifest at path.
+func ParseFile(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
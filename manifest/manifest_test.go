@@ -0,0 +1,180 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	xmlManifest := []byte(`<manifest>
+	<imports>
+		<import name="base" remote="https://example.com/base" manifest="manifest/base"/>
+	</imports>
+	<projects>
+		<project name="foo" path="vendor/foo" remote="https://example.com/foo" revision="abc123" remotebranch="master"/>
+	</projects>
+</manifest>`)
+
+	m, err := Parse(xmlManifest)
+	if err != nil {
+		t.Fatalf("Parse(xml) returned an error: %s", err)
+	}
+	if len(m.Projects) != 1 || m.Projects[0].Path != "vendor/foo" || m.Projects[0].Revision != "abc123" {
+		t.Errorf("Parse(xml) projects = %+v, want a single vendor/foo@abc123", m.Projects)
+	}
+	if len(m.Imports) != 1 || m.Imports[0].Remote != "https://example.com/base" {
+		t.Errorf("Parse(xml) imports = %+v, want a single import of https://example.com/base", m.Imports)
+	}
+
+	yamlManifest := []byte(`
+imports:
+  - name: base
+    remote: https://example.com/base
+    manifest: manifest/base
+projects:
+  - name: foo
+    path: vendor/foo
+    remote: https://example.com/foo
+    revision: abc123
+    remotebranch: master
+`)
+
+	m, err = Parse(yamlManifest)
+	if err != nil {
+		t.Fatalf("Parse(yaml) returned an error: %s", err)
+	}
+	if len(m.Projects) != 1 || m.Projects[0].Path != "vendor/foo" || m.Projects[0].Revision != "abc123" {
+		t.Errorf("Parse(yaml) projects = %+v, want a single vendor/foo@abc123", m.Projects)
+	}
+	if len(m.Imports) != 1 || m.Imports[0].Remote != "https://example.com/base" {
+		t.Errorf("Parse(yaml) imports = %+v, want a single import of https://example.com/base", m.Imports)
+	}
+}
+
+// writeManifestFile writes an XML manifest with the given projects to
+// dir/name and returns its path.
+func writeManifestFile(t *testing.T, dir, name string, m *Manifest) string {
+	t.Helper()
+
+	manifestPath := path.Join(dir, name)
+	if err := os.MkdirAll(path.Dir(manifestPath), 0755); err != nil {
+		t.Fatalf("failed creating manifest dir: %s", err)
+	}
+
+	var body string
+	for _, p := range m.Projects {
+		body += `<project name="` + p.Name + `" path="` + p.Path + `" remote="` + p.Remote + `" revision="` + p.Revision + `"/>`
+	}
+	var imports string
+	for _, imp := range m.Imports {
+		imports += `<import name="` + imp.Name + `" remote="` + imp.Remote + `" manifest="` + imp.Manifest + `"/>`
+	}
+
+	content := "<manifest><imports>" + imports + "</imports><projects>" + body + "</projects></manifest>"
+	if err := ioutil.WriteFile(manifestPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed writing manifest file: %s", err)
+	}
+	return manifestPath
+}
+
+func TestResolve(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "godm-manifest-resolve-test")
+	if err != nil {
+		t.Fatalf("failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeManifestFile(t, tmpDir, "base", &Manifest{
+		Projects: []Project{{Name: "bar", Path: "vendor/bar", Remote: "https://example.com/bar", Revision: "def"}},
+	})
+
+	root := &Manifest{
+		Imports: []Import{
+			{Name: "base", Remote: "https://example.com/base", Manifest: "base"},
+		},
+		Projects: []Project{
+			{Name: "foo", Path: "vendor/foo", Remote: "https://example.com/foo", Revision: "abc"},
+		},
+	}
+
+	fetch := func(remoteURI string) (string, error) {
+		return tmpDir, nil
+	}
+
+	rootManifestPath := path.Join(tmpDir, "root")
+	projects, err := Resolve(root, rootManifestPath, fetch)
+	if err != nil {
+		t.Fatalf("Resolve returned an error: %s", err)
+	}
+	if len(projects) != 2 || projects[0].Path != "vendor/foo" || projects[1].Path != "vendor/bar" {
+		t.Errorf("Resolve = %+v, want [vendor/foo, vendor/bar]", projects)
+	}
+}
+
+// TestResolve_DiamondImport ensures that two sibling imports pointing at the
+// same shared manifest (a diamond, not a cycle) both resolve successfully.
+func TestResolve_DiamondImport(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "godm-manifest-diamond-test")
+	if err != nil {
+		t.Fatalf("failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeManifestFile(t, tmpDir, "common", &Manifest{
+		Projects: []Project{{Name: "shared", Path: "vendor/shared", Remote: "https://example.com/shared", Revision: "ghi"}},
+	})
+	writeManifestFile(t, tmpDir, "a", &Manifest{
+		Imports: []Import{{Name: "common", Remote: "https://example.com/common", Manifest: "common"}},
+	})
+	writeManifestFile(t, tmpDir, "b", &Manifest{
+		Imports: []Import{{Name: "common", Remote: "https://example.com/common", Manifest: "common"}},
+	})
+
+	root := &Manifest{
+		Imports: []Import{
+			{Name: "a", Remote: "https://example.com/a", Manifest: "a"},
+			{Name: "b", Remote: "https://example.com/b", Manifest: "b"},
+		},
+	}
+
+	fetch := func(remoteURI string) (string, error) {
+		return tmpDir, nil
+	}
+
+	projects, err := Resolve(root, path.Join(tmpDir, "root"), fetch)
+	if err != nil {
+		t.Fatalf("Resolve returned an error for a diamond import: %s", err)
+	}
+	if len(projects) != 2 || projects[0].Path != "vendor/shared" || projects[1].Path != "vendor/shared" {
+		t.Errorf("Resolve = %+v, want vendor/shared pulled in via both a and b", projects)
+	}
+}
+
+func TestResolve_ImportCycle(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "godm-manifest-cycle-test")
+	if err != nil {
+		t.Fatalf("failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeManifestFile(t, tmpDir, "a", &Manifest{
+		Imports: []Import{{Name: "b", Remote: "https://example.com/b", Manifest: "b"}},
+	})
+	writeManifestFile(t, tmpDir, "b", &Manifest{
+		Imports: []Import{{Name: "a", Remote: "https://example.com/a", Manifest: "a"}},
+	})
+
+	root := &Manifest{
+		Imports: []Import{{Name: "a", Remote: "https://example.com/a", Manifest: "a"}},
+	}
+
+	fetch := func(remoteURI string) (string, error) {
+		return tmpDir, nil
+	}
+
+	if _, err := Resolve(root, path.Join(tmpDir, "root"), fetch); err != ErrImportCycle {
+		t.Errorf("Resolve with an A -> B -> A import cycle = %v, want ErrImportCycle", err)
+	}
+}
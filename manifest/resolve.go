@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// ErrImportCycle is returned by Resolve when an <import> chain refers back
+// to a manifest that is already being resolved.
+var ErrImportCycle = errors.New("Import cycle detected in manifest")
+
+// Fetcher clones a remote manifest repository and returns the local
+// directory it was cloned into, so Resolve can read further manifests from
+// disk without this package depending on a specific VCS backend.
+type Fetcher func(remoteURI string) (dir string, err error)
+
+// Resolve flattens m and all of its <import>s into a single ordered list of
+// Projects. fetch is called once for every <import>, with the import's
+// remote, to materialize the git repository holding its manifest.
+func Resolve(m *Manifest, manifestPath string, fetch Fetcher) ([]Project, error) {
+	visited := map[string]bool{manifestPath: true}
+	return resolve(m, fetch, visited)
+}
+
+func resolve(m *Manifest, fetch Fetcher, visited map[string]bool) ([]Project, error) {
+	projects := append([]Project{}, m.Projects...)
+
+	for _, imp := range m.Imports {
+		// Each import is fetched into its own fresh temp dir, so its identity
+		// for cycle detection must come from what it points at (remote +
+		// manifest path), not from that local, always-unique directory.
+		importIdentity := imp.Remote + "#" + imp.Manifest
+		if visited[importIdentity] {
+			return nil, ErrImportCycle
+		}
+		visited[importIdentity] = true
+
+		importDir, err := fetch(imp.Remote)
+		if err != nil {
+			return nil, fmt.Errorf("resolving import %q: %s", imp.Name, err)
+		}
+
+		importManifestPath := path.Join(importDir, imp.Manifest)
+		importedManifest, err := ParseFile(importManifestPath)
+		if err != nil {
+			return nil, err
+		}
+
+		importedProjects, err := resolve(importedManifest, fetch, visited)
+		// visited only needs to track the current import chain (ancestors),
+		// not every import ever seen: once this branch is done, forget it so
+		// a sibling branch legitimately importing the same manifest (a
+		// diamond dependency) isn't mistaken for a cycle.
+		delete(visited, importIdentity)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, importedProjects...)
+	}
+
+	return projects, nil
+}
@@ -12,6 +12,55 @@ type RemoteGitProject interface {
 	Project
 	// GetURI returns a fetchable URI for the repository
 	GetGitURI() string
+	// GetReference returns the ref (branch, tag or commit) to check out after
+	// cloning. Defaults to "master" when the URI carried no `#ref` fragment.
+	GetReference() string
+	// GetSubdir returns the subdirectory of the repository to use as the
+	// vendored project root, or "" if the whole repository should be used.
+	GetSubdir() string
+}
+
+// defaultGitReference is used when a RemoteGitProject URI carries no `#ref` fragment.
+const defaultGitReference = "master"
+
+// parseRemoteGitURI splits a Docker/BuildKit-style `<repo>#<ref>:<subdir>` URI
+// into its base repository URI, ref and optional subdir. ref is "" when the
+// fragment is absent or empty, leaving it to the caller to decide how to
+// handle "no ref requested" (e.g. RemoteGitProject.GetReference falls back
+// to defaultGitReference, while Install treats "" as "whatever the remote's
+// default branch is"). This works unmodified with `git://`, `https://`,
+// `ssh://` and scp-like `git@host:path` forms, since only the `#` suffix (if
+// any) is ever split off.
+func parseRemoteGitURI(uri string) (baseURI, ref, subdir string) {
+	baseURI = uri
+
+	fragmentIndex := strings.Index(uri, "#")
+	if fragmentIndex == -1 {
+		return baseURI, ref, subdir
+	}
+
+	baseURI = uri[:fragmentIndex]
+	fragment := uri[fragmentIndex+1:]
+
+	if colonIndex := strings.Index(fragment, ":"); colonIndex != -1 {
+		ref, subdir = fragment[:colonIndex], fragment[colonIndex+1:]
+	} else {
+		ref = fragment
+	}
+
+	return baseURI, ref, subdir
+}
+
+// NewRemoteGitProject builds a RemoteGitProject from a URI that may carry a
+// Docker/BuildKit-style `#ref:subdir` fragment, e.g.
+// `github.com/foo/bar#v1.2.3:pkg/sub`.
+func NewRemoteGitProject(uri string) RemoteGitProject {
+	baseURI, ref, subdir := parseRemoteGitURI(uri)
+	return &remoteGitProject{
+		uri:    baseURI,
+		ref:    ref,
+		subdir: subdir,
+	}
 }
 
 type LocalGitProject interface {
@@ -23,22 +72,65 @@ type LocalGitProject interface {
 	// GetRemote returns the RemoteGitProject if possible
 	// Returns nil if there is no remote.
 	GetRemote() (RemoteGitProject, error)
+	// Sync reconciles the vendor tree against the manifest at manifestPath:
+	// projects missing from disk are cloned, existing ones are checked out
+	// to their pinned revision (without fetching, so that revision must
+	// already be present locally), and vendored directories no longer listed
+	// in the manifest are pruned.
+	Sync(manifestPath string) error
 }
 
 type remoteGitProject struct {
-	uri string
+	uri    string
+	ref    string
+	subdir string
 }
 
 func (self *remoteGitProject) GetGitURI() string {
 	return self.uri
 }
 
+func (self *remoteGitProject) GetReference() string {
+	if self.ref == "" {
+		return defaultGitReference
+	}
+	return self.ref
+}
+
+func (self *remoteGitProject) GetSubdir() string {
+	return self.subdir
+}
+
 func (self *remoteGitProject) Install(destination string) (LocalProject, error) {
 	destination = path.Clean(destination)
-	if err := git.Service.Clone(destination, self.GetGitURI()); err != nil {
+	cloneOptions := git.CloneOptions{
+		Recursive:         true,
+		ShallowSubmodules: true,
+	}
+	// When no ref was requested, a shallow clone of the remote's actual
+	// default branch (whatever it's named) is exactly what we want, and no
+	// further checkout is needed or safe: the remote's default branch isn't
+	// necessarily "master" (e.g. GitHub repos created after 2020 default to
+	// "main"), so checking out defaultGitReference here could fail with
+	// "pathspec 'master' did not match". Only shallow-clone and skip the
+	// checkout when self.ref is unset; a non-default ref (tag, commit or
+	// other branch) needs a full clone followed by an explicit checkout.
+	if self.ref == "" {
+		cloneOptions.Depth = 1
+	}
+	if err := git.Service.Clone(destination, self.GetGitURI(), cloneOptions); err != nil {
 		return nil, err
 	}
-	return NewGitProjectFromPath(destination, destination)
+	if self.ref != "" {
+		if err := git.Service.CheckoutCommit(destination, self.ref); err != nil {
+			return nil, err
+		}
+	}
+	projectRoot := destination
+	if self.subdir != "" {
+		projectRoot = path.Join(destination, self.subdir)
+	}
+	return NewGitProjectFromPath(projectRoot, destination)
 }
 
 type localGitProject struct {
@@ -51,23 +143,44 @@ type localGitProject struct {
 
 var _ LocalGitProject = (*localGitProject)(nil)
 
+// NewGitProjectFromPath builds a project rooted at path, which must sit
+// inside the git repository whose toplevel is rootPath or one of its
+// ancestor directories. path is
+// used as the project's base dir as-is (rather than the repo's git
+// toplevel) so that a subdir of a repository can be vendored on its own,
+// with the rest of the working tree ignored.
 func NewGitProjectFromPath(path, rootPath string) (*localGitProject, error) {
 	gitBaseDir, err := git.Service.GetRootDir(path)
 	if err != nil {
 		return nil, err
 	}
-	if len(gitBaseDir) < len(rootPath) {
+	// gitBaseDir must be rootPath itself, or a proper ancestor directory of
+	// it; a plain length comparison would accept an unrelated git root whose
+	// path string just happens to be longer.
+	if gitBaseDir != rootPath && !strings.HasPrefix(rootPath, gitBaseDir+"/") {
 		return nil, git.ErrNotAGitRepository
 	}
 	project := &localGitProject{
-		ProjectNoVCL: *(NewProjectNoVCL(gitBaseDir)),
+		ProjectNoVCL: *(NewProjectNoVCL(path)),
 	}
 	project.Recursive = true
 	return project, nil
 }
 
 func NewlocalGitProjectFromURI(uri, reference string) *localGitProject {
-	return &localGitProject{}
+	baseURI, ref, subdir := parseRemoteGitURI(uri)
+	if reference != "" {
+		ref = reference
+	}
+	return &localGitProject{
+		remote: &remoteGitProject{
+			uri:    baseURI,
+			ref:    ref,
+			subdir: subdir,
+		},
+		remoteChecked: true,
+		reference:     ref,
+	}
 }
 
 func (self *localGitProject) GetReference() (reference string, err error) {
@@ -104,6 +217,17 @@ func (self *localGitProject) GetRemote() (RemoteGitProject, error) {
 
 }
 
+// AddVendorByImportPath deduces the VCS root and clone URL for a bare import
+// path such as "github.com/foo/bar" and vendors it, so callers don't have to
+// hand-supply the git URI for every dependency.
+func (self *localGitProject) AddVendorByImportPath(importPath string) (Vendor, error) {
+	resolved, err := NewImportPathResolver().Resolve(importPath)
+	if err != nil {
+		return nil, err
+	}
+	return self.AddVendor(importPath, NewRemoteGitProject(resolved.CloneURL))
+}
+
 // AddVendor as a git submodule if possible, or else by simply copying it
 func (self *localGitProject) AddVendor(importPath string, project Project) (Vendor, error) {
 	vendors, err := self.GetVendors()
@@ -122,8 +246,42 @@ func (self *localGitProject) AddVendor(importPath string, project Project) (Vend
 	absoluteTargetPath := path.Join(self.GetBaseDir(), relativeTargetPath)
 	switch typedProject := project.(type) {
 	case RemoteGitProject:
-		git.Service.AddSubmodule(self.GetBaseDir(), typedProject.GetGitURI(), relativeTargetPath)
-		v.LocalProject, err = NewGitProjectFromPath(absoluteTargetPath, absoluteTargetPath)
+		err = git.Service.AddSubmodule(self.GetBaseDir(), typedProject.GetGitURI(), relativeTargetPath)
+		if err != nil {
+			return nil, err
+		}
+
+		errorHandler := func() {
+			git.Service.RemoveSubmodule(self.GetBaseDir(), relativeTargetPath)
+		}
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				errorHandler()
+				panic(panicErr)
+			}
+		}()
+
+		err = git.Service.CheckoutCommit(absoluteTargetPath, typedProject.GetReference())
+		if err != nil {
+			errorHandler()
+			return nil, err
+		}
+
+		if err = git.Service.InitSubmodules(absoluteTargetPath, true); err != nil {
+			errorHandler()
+			return nil, err
+		}
+		if err = git.Service.UpdateSubmodules(absoluteTargetPath, true); err != nil {
+			errorHandler()
+			return nil, err
+		}
+
+		vendorRoot := absoluteTargetPath
+		if subdir := typedProject.GetSubdir(); subdir != "" {
+			vendorRoot = path.Join(absoluteTargetPath, subdir)
+		}
+
+		v.LocalProject, err = NewGitProjectFromPath(vendorRoot, absoluteTargetPath)
 		return v, err
 	case LocalGitProject:
 		remote, err := typedProject.GetRemote()
@@ -157,6 +315,15 @@ func (self *localGitProject) AddVendor(importPath string, project Project) (Vend
 				return nil, err
 			}
 
+			if err = git.Service.InitSubmodules(absoluteTargetPath, true); err != nil {
+				errorHandler()
+				return nil, err
+			}
+			if err = git.Service.UpdateSubmodules(absoluteTargetPath, true); err != nil {
+				errorHandler()
+				return nil, err
+			}
+
 			v.LocalProject, err = NewGitProjectFromPath(absoluteTargetPath, absoluteTargetPath)
 
 			return v, err
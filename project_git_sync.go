@@ -0,0 +1,114 @@
+package godm
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hectorj/godm/git"
+	"github.com/hectorj/godm/manifest"
+)
+
+// jiriHeadFileName mirrors jiri's convention of stamping the pinned revision
+// into the vendored repo's .git directory, so a later Sync can detect drift
+// without hitting the network.
+const jiriHeadFileName = "JIRI_HEAD"
+
+// Sync reconciles the project's vendor tree against the manifest at
+// manifestPath.
+func (self *localGitProject) Sync(manifestPath string) error {
+	rootManifest, err := manifest.ParseFile(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	fetchImport := func(remoteURI string) (string, error) {
+		importDir, err := ioutil.TempDir("", "godm-manifest-import")
+		if err != nil {
+			return "", err
+		}
+		if err := git.Service.Clone(importDir, remoteURI, git.CloneOptions{Depth: 1}); err != nil {
+			return "", err
+		}
+		return importDir, nil
+	}
+
+	projects, err := manifest.Resolve(rootManifest, manifestPath, fetchImport)
+	if err != nil {
+		return err
+	}
+
+	listedPaths := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		listedPaths[p.Path] = true
+
+		if err := self.syncProject(p); err != nil {
+			return err
+		}
+	}
+
+	return self.pruneUnlistedVendors(listedPaths)
+}
+
+// syncProject clones p if it is missing from the working tree, or checks out
+// its pinned revision if it is already present, then stamps the resulting
+// checkout with a JIRI_HEAD file. An existing checkout is not fetched: its
+// pinned revision must already be present locally. If an existing
+// checkout's JIRI_HEAD already matches p.Revision, it is assumed to be up to
+// date and left untouched.
+func (self *localGitProject) syncProject(p manifest.Project) error {
+	absoluteTargetPath := path.Join(self.GetBaseDir(), p.Path)
+
+	if _, err := os.Stat(absoluteTargetPath); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		cloneOptions := git.CloneOptions{Branch: p.RemoteBranch, Recursive: true}
+		if err := git.Service.Clone(absoluteTargetPath, p.Remote, cloneOptions); err != nil {
+			return err
+		}
+	} else if readJiriHead(absoluteTargetPath) == p.Revision {
+		return nil
+	}
+
+	if err := git.Service.CheckoutCommit(absoluteTargetPath, p.Revision); err != nil {
+		return err
+	}
+
+	return writeJiriHead(absoluteTargetPath, p.Revision)
+}
+
+// pruneUnlistedVendors removes vendored dependencies that are no longer
+// present in the manifest.
+func (self *localGitProject) pruneUnlistedVendors(listedPaths map[string]bool) error {
+	vendors, err := self.GetVendors()
+	if err != nil {
+		return err
+	}
+
+	for importPath := range vendors {
+		if listedPaths[path.Join("vendor", importPath)] {
+			continue
+		}
+		if err := self.RemoveVendor(importPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeJiriHead(repoDir, revision string) error {
+	return ioutil.WriteFile(path.Join(repoDir, ".git", jiriHeadFileName), []byte(revision+"\n"), 0644)
+}
+
+// readJiriHead returns the revision stamped by a prior writeJiriHead, or ""
+// if the checkout has no stamp yet (or it can't be read).
+func readJiriHead(repoDir string) string {
+	data, err := ioutil.ReadFile(path.Join(repoDir, ".git", jiriHeadFileName))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
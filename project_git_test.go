@@ -0,0 +1,57 @@
+package godm
+
+import "testing"
+
+func TestParseRemoteGitURI(t *testing.T) {
+	testCases := map[string]struct {
+		uri            string
+		expectedURI    string
+		expectedRef    string
+		expectedSubdir string
+	}{
+		"no fragment": {
+			uri:         "https://github.com/foo/bar",
+			expectedURI: "https://github.com/foo/bar",
+			expectedRef: defaultGitReference,
+		},
+		"ref only": {
+			uri:         "https://github.com/foo/bar#v1.2.3",
+			expectedURI: "https://github.com/foo/bar",
+			expectedRef: "v1.2.3",
+		},
+		"ref and subdir": {
+			uri:            "https://github.com/foo/bar#v1.2.3:pkg/sub",
+			expectedURI:    "https://github.com/foo/bar",
+			expectedRef:    "v1.2.3",
+			expectedSubdir: "pkg/sub",
+		},
+		"empty ref before subdir defaults to master": {
+			uri:            "https://github.com/foo/bar#:pkg/sub",
+			expectedURI:    "https://github.com/foo/bar",
+			expectedRef:    defaultGitReference,
+			expectedSubdir: "pkg/sub",
+		},
+		"scp-like with fragment": {
+			uri:         "git@github.com:foo/bar#develop",
+			expectedURI: "git@github.com:foo/bar",
+			expectedRef: "develop",
+		},
+	}
+
+	for caseName, testCase := range testCases {
+		baseURI, _, subdir := parseRemoteGitURI(testCase.uri)
+		// The parser leaves ref empty when none is given; the master default
+		// is applied by GetReference, so the ref is checked through it.
+		ref := NewRemoteGitProject(testCase.uri).GetReference()
+
+		if baseURI != testCase.expectedURI {
+			t.Errorf("%q: expected base URI %q, got %q", caseName, testCase.expectedURI, baseURI)
+		}
+		if ref != testCase.expectedRef {
+			t.Errorf("%q: expected ref %q, got %q", caseName, testCase.expectedRef, ref)
+		}
+		if subdir != testCase.expectedSubdir {
+			t.Errorf("%q: expected subdir %q, got %q", caseName, testCase.expectedSubdir, subdir)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package godm
+
+import "github.com/hectorj/godm/git"
+
+// SetVCSBackend overrides the VCS backend used for every git operation
+// godm performs, e.g. to swap the default exec-based backend for the
+// pure-Go go-git one at runtime, or to inject a stub in tests.
+func SetVCSBackend(service git.VCSService) {
+	git.SetService(service)
+}